@@ -0,0 +1,50 @@
+package md2man
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/russross/blackfriday/v2"
+)
+
+func renderImage(t *testing.T, opts ...RoffRendererOption) string {
+	t.Helper()
+	renderer := NewRoffRenderer(opts...)
+	doc := blackfriday.Run([]byte("![alt text](http://example.com/img.png)\n"),
+		blackfriday.WithRenderer(renderer),
+		blackfriday.WithExtensions(renderer.GetExtensions()))
+	return string(doc)
+}
+
+func TestImageInlineAltURLIsTheDefault(t *testing.T) {
+	out := renderImage(t)
+
+	if !strings.Contains(out, "alt text") {
+		t.Fatalf("expected the default mode to render the alt text, got:\n%s", out)
+	}
+	if !strings.Contains(out, linkTag+"http://example.com/img.png"+linkCloseTag) {
+		t.Fatalf("expected the default mode to render the image URL as a link, got:\n%s", out)
+	}
+}
+
+func TestImageAltOnly(t *testing.T) {
+	out := renderImage(t, WithImageRenderMode(ImageAltOnly))
+
+	if !strings.Contains(out, "alt text") {
+		t.Fatalf("expected ImageAltOnly to render the alt text, got:\n%s", out)
+	}
+	if strings.Contains(out, "http://example.com/img.png") {
+		t.Fatalf("expected ImageAltOnly to drop the URL, got:\n%s", out)
+	}
+}
+
+func TestImageSkip(t *testing.T) {
+	out := renderImage(t, WithImageRenderMode(ImageSkip))
+
+	if strings.Contains(out, "alt text") {
+		t.Fatalf("expected ImageSkip to drop the alt text, got:\n%s", out)
+	}
+	if strings.Contains(out, "http://example.com/img.png") {
+		t.Fatalf("expected ImageSkip to drop the URL, got:\n%s", out)
+	}
+}
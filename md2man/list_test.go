@@ -0,0 +1,36 @@
+package md2man
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestNestedMixedLists(t *testing.T) {
+	input := []byte("- outer item\n\n  1. mid item\n\n     - inner item\n")
+	out := string(Render(input))
+
+	if got := strings.Count(out, listTag); got < 2 {
+		t.Fatalf("expected each nested list to open its own .RS block, got %d in:\n%s", got, out)
+	}
+	if open, close := strings.Count(out, listTag), strings.Count(out, listCloseTag); open != close {
+		t.Fatalf(".RS/.RE are unbalanced (%d opens, %d closes) in:\n%s", open, close, out)
+	}
+	if !strings.Contains(out, ".IP \\(bu 2\n") {
+		t.Fatalf("expected the outer/inner bullet items to render, got:\n%s", out)
+	}
+	if !strings.Contains(out, `.IP "  1." 5`) {
+		t.Fatalf("expected the mid ordered item to start counting at 1, got:\n%s", out)
+	}
+}
+
+func TestDefinitionListWithBulletSublist(t *testing.T) {
+	input := []byte("Term\n:   Definition\n\n    - sub bullet one\n    - sub bullet two\n")
+	out := string(Render(input))
+
+	if !strings.Contains(out, arglistTag) {
+		t.Fatalf("expected the term/definition pair to render as a .TP entry, got:\n%s", out)
+	}
+	if !strings.Contains(out, listTag) || !strings.Contains(out, listCloseTag) {
+		t.Fatalf("expected the bullet sub-list nested in the definition to be indented with .RS/.RE, got:\n%s", out)
+	}
+}
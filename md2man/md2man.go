@@ -0,0 +1,16 @@
+package md2man
+
+import "github.com/russross/blackfriday/v2"
+
+// Render converts a markdown document into roff, suitable for consumption by
+// man(1). A leading pandoc-style title line or YAML front-matter block, if
+// present, is parsed into the man page's .TH header; otherwise the .TH line
+// falls back to the document's first level-1 heading.
+func Render(doc []byte) []byte {
+	renderer := NewRoffRenderer()
+	doc = renderer.parseTitleBlock(doc)
+	return blackfriday.Run(doc,
+		blackfriday.WithRenderer(renderer),
+		blackfriday.WithExtensions(renderer.GetExtensions()),
+	)
+}
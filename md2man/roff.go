@@ -6,15 +6,90 @@ import (
 	"os"
 	"strings"
 
-	"github.com/russross/blackfriday"
+	"github.com/russross/blackfriday/v2"
 )
 
+// roffRenderer implements blackfriday.Renderer, rendering a parsed markdown
+// document as a roff(7)/man(7) document.
 type roffRenderer struct {
-	extensions   blackfriday.Extensions
-	ListCounters []int
-	firstHeader  bool
-	defineTerm   bool
-	inList       bool
+	extensions  blackfriday.Extensions
+	listStack   []listFrame
+	firstHeader bool
+	defineTerm  bool
+	imageMode   ImageRenderMode
+	tableExpand bool
+	tableWidths []int
+	titleBlock  *manTitleBlock
+}
+
+// roffRenderer must satisfy blackfriday.Renderer so that downstream
+// consumers can pass it directly to blackfriday.Run via blackfriday.WithRenderer.
+var _ blackfriday.Renderer = &roffRenderer{}
+
+// listFrame tracks the state of a single list while it is being rendered,
+// so that nested and mixed ordered/unordered lists each keep their own
+// counter and indentation instead of sharing a single global one.
+type listFrame struct {
+	flags   blackfriday.ListType
+	counter int
+}
+
+func (r *roffRenderer) pushList(flags blackfriday.ListType) {
+	counter := 0
+	if flags&blackfriday.ListTypeOrdered != 0 {
+		counter = 1
+	}
+	r.listStack = append(r.listStack, listFrame{flags: flags, counter: counter})
+}
+
+func (r *roffRenderer) popList() {
+	r.listStack = r.listStack[:len(r.listStack)-1]
+}
+
+func (r *roffRenderer) currentList() *listFrame {
+	return &r.listStack[len(r.listStack)-1]
+}
+
+// ImageRenderMode controls how blackfriday.Image nodes are rendered in the
+// generated roff output.
+type ImageRenderMode int
+
+const (
+	// ImageInlineAltURL renders the image's alt text followed by its URL
+	// formatted as a roff link. This is the default.
+	ImageInlineAltURL ImageRenderMode = iota
+	// ImageAltOnly renders only the image's alt text, dropping the URL.
+	ImageAltOnly
+	// ImageSkip omits images (and their alt text) from the output entirely,
+	// matching the behavior of earlier versions of this renderer.
+	ImageSkip
+)
+
+// RoffRendererOption configures a roffRenderer created by NewRoffRenderer.
+type RoffRendererOption func(*roffRenderer)
+
+// WithImageRenderMode selects how images are rendered in the generated roff
+// output; it defaults to ImageInlineAltURL.
+func WithImageRenderMode(mode ImageRenderMode) RoffRendererOption {
+	return func(r *roffRenderer) {
+		r.imageMode = mode
+	}
+}
+
+// WithTableExpand makes rendered tables stretch to fill the full line width,
+// via tbl(1)'s "expand" option, instead of sizing to their natural content width.
+func WithTableExpand(expand bool) RoffRendererOption {
+	return func(r *roffRenderer) {
+		r.tableExpand = expand
+	}
+}
+
+// WithTableColumnWidths fixes the rendered width, in characters, of each
+// table column in order; a zero entry leaves that column's width to tbl(1).
+func WithTableColumnWidths(widths ...int) RoffRendererOption {
+	return func(r *roffRenderer) {
+		r.tableWidths = widths
+	}
 }
 
 const (
@@ -41,15 +116,14 @@ const (
 	listTag          = "\n.RS\n"
 	listCloseTag     = "\n.RE\n"
 	arglistTag       = "\n.TP\n"
-	tableStart       = "\n.TS\nallbox;\n"
 	tableEnd         = "\n.TE\n"
 	tableCellStart   = "\nT{\n"
 	tableCellEnd     = "\nT}\n"
 )
 
-// NewRoffRenderer creates a new blackfriday Renderer for generating roff documents
-// from markdown
-func NewRoffRenderer() *roffRenderer {
+// NewRoffRenderer creates a new blackfriday.Renderer for generating roff documents
+// from markdown, suitable for passing directly to blackfriday.WithRenderer.
+func NewRoffRenderer(opts ...RoffRendererOption) *roffRenderer {
 	var extensions blackfriday.Extensions
 
 	extensions |= blackfriday.NoIntraEmphasis
@@ -59,9 +133,14 @@ func NewRoffRenderer() *roffRenderer {
 	extensions |= blackfriday.Footnotes
 	extensions |= blackfriday.Titleblock
 	extensions |= blackfriday.DefinitionLists
-	return &roffRenderer{
+	r := &roffRenderer{
 		extensions: extensions,
+		imageMode:  ImageInlineAltURL,
+	}
+	for _, opt := range opts {
+		opt(r)
 	}
+	return r
 }
 
 func (r *roffRenderer) GetExtensions() blackfriday.Extensions {
@@ -71,7 +150,12 @@ func (r *roffRenderer) GetExtensions() blackfriday.Extensions {
 func (r *roffRenderer) RenderHeader(w io.Writer, ast *blackfriday.Node) {
 	// disable hyphenation
 	io.WriteString(w, ".nh\n")
-	return
+	if r.titleBlock != nil {
+		out(w, r.titleBlock.thLine())
+		// the title block already produced the .TH line; the document's
+		// first H1 (if any) becomes a regular section heading
+		r.firstHeader = true
+	}
 }
 
 func (r *roffRenderer) RenderFooter(w io.Writer, ast *blackfriday.Node) {
@@ -82,18 +166,7 @@ func (r *roffRenderer) RenderNode(w io.Writer, node *blackfriday.Node, entering
 
 	switch node.Type {
 	case blackfriday.Text:
-		var (
-			start, end string
-		)
-		if node.Parent.Type == blackfriday.TableCell {
-			if len(node.Literal) > 30 {
-				start = tableCellStart
-				end = tableCellEnd
-			}
-		}
-		out(w, start)
 		escapeSpecialChars(w, node.Literal)
-		out(w, end)
 	case blackfriday.Softbreak:
 		out(w, crTag)
 	case blackfriday.Hardbreak:
@@ -117,8 +190,14 @@ func (r *roffRenderer) RenderNode(w io.Writer, node *blackfriday.Node, entering
 			out(w, linkCloseTag)
 		}
 	case blackfriday.Image:
-		// ignore images
-		return blackfriday.SkipChildren
+		if r.imageMode == ImageSkip {
+			return blackfriday.SkipChildren
+		}
+		// entering: fall through and let the walker render the alt text,
+		// which blackfriday represents as the image's Text children.
+		if !entering && r.imageMode == ImageInlineAltURL {
+			out(w, linkTag+string(node.LinkData.Destination)+linkCloseTag)
+		}
 	case blackfriday.Code:
 		out(w, codespanTag)
 		escapeSpecialChars(w, node.Literal)
@@ -127,7 +206,7 @@ func (r *roffRenderer) RenderNode(w io.Writer, node *blackfriday.Node, entering
 		break
 	case blackfriday.Paragraph:
 		// roff .PP markers break lists
-		if r.inList {
+		if len(r.listStack) > 0 {
 			return blackfriday.GoToNext
 		}
 		if entering {
@@ -160,31 +239,29 @@ func (r *roffRenderer) RenderNode(w io.Writer, node *blackfriday.Node, entering
 	case blackfriday.HorizontalRule:
 		out(w, hruleTag)
 	case blackfriday.List:
-		openTag := listTag
-		closeTag := listCloseTag
-		if node.ListFlags&blackfriday.ListTypeDefinition != 0 {
-			// tags for definition lists handled within Item node
-			openTag = ""
-			closeTag = ""
-		}
+		// a list nested inside another list must always be indented with
+		// .RS/.RE so it renders as a sub-list rather than running back out
+		// to the parent's margin; a top-level definition list instead relies
+		// on .TP indentation handled entirely within the Item case.
 		if entering {
-			r.inList = true
-			if node.ListFlags&blackfriday.ListTypeOrdered != 0 {
-				r.ListCounters = append(r.ListCounters, 1)
+			nested := len(r.listStack) > 0
+			r.pushList(node.ListFlags)
+			if nested || node.ListFlags&blackfriday.ListTypeDefinition == 0 {
+				out(w, listTag)
 			}
-			out(w, openTag)
 		} else {
-			if node.ListFlags&blackfriday.ListTypeOrdered != 0 {
-				r.ListCounters = r.ListCounters[:len(r.ListCounters)-1]
+			r.popList()
+			nested := len(r.listStack) > 0
+			if nested || node.ListFlags&blackfriday.ListTypeDefinition == 0 {
+				out(w, listCloseTag)
 			}
-			out(w, closeTag)
-			r.inList = false
 		}
 	case blackfriday.Item:
 		if entering {
 			if node.ListFlags&blackfriday.ListTypeOrdered != 0 {
-				out(w, fmt.Sprintf(".IP \"%3d.\" 5\n", r.ListCounters[len(r.ListCounters)-1]))
-				r.ListCounters[len(r.ListCounters)-1]++
+				cur := r.currentList()
+				out(w, fmt.Sprintf(".IP \"%3d.\" 5\n", cur.counter))
+				cur.counter++
 			} else if node.ListFlags&blackfriday.ListTypeDefinition != 0 {
 				// state machine for handling terms and following definitions
 				// since blackfriday does not distinguish them properly, nor
@@ -207,35 +284,43 @@ func (r *roffRenderer) RenderNode(w io.Writer, node *blackfriday.Node, entering
 		out(w, codeCloseTag)
 	case blackfriday.Table:
 		if entering {
-			out(w, tableStart)
-			//call walker to count cells (and rows?) so format section can be produced
-			columns := countColumns(node)
-			out(w, strings.Repeat("l ", columns)+"\n")
-			out(w, strings.Repeat("l ", columns)+".\n")
+			opts := "allbox"
+			if r.tableExpand {
+				opts += " expand"
+			}
+			out(w, "\n.TS\n"+opts+";\n")
+			// tbl(1) needs the column alignment/format section up front, before
+			// any row data, so walk the header row once to derive it.
+			aligns := tableColumnAligns(node)
+			out(w, r.tableColumnSpec(aligns, true)+"\n")
+			out(w, r.tableColumnSpec(aligns, false)+".\n")
 		} else {
 			out(w, tableEnd)
 		}
 	case blackfriday.TableCell:
-		var (
-			start, end string
-		)
-		if node.IsHeader {
-			start = codespanTag
-			end = codespanCloseTag
-		}
+		wrap := tableCellNeedsWrap(node)
 		if entering {
-			if node.Prev.Type == blackfriday.TableCell {
-				out(w, "\t"+start)
+			if node.Prev != nil && node.Prev.Type == blackfriday.TableCell {
+				out(w, "\t")
 			}
-		} else {
-			out(w, end)
+			if wrap {
+				out(w, tableCellStart)
+			}
+		} else if wrap {
+			out(w, tableCellEnd)
 		}
 	case blackfriday.TableHead:
+		if !entering {
+			// underline the header row to separate it from the table body
+			out(w, "\n_\n")
+		}
 	case blackfriday.TableBody:
 		// no action as cell entries do all the nroff formatting
 		return blackfriday.GoToNext
 	case blackfriday.TableRow:
-		out(w, "\n")
+		if entering {
+			out(w, "\n")
+		}
 	default:
 		fmt.Fprintf(os.Stderr, "WARNING: go-md2man does not handle node type "+node.Type.String())
 	}
@@ -243,25 +328,73 @@ func (r *roffRenderer) RenderNode(w io.Writer, node *blackfriday.Node, entering
 	return blackfriday.GoToNext
 }
 
-// because roff format requires knowing the column count before outputting any table
-// data we need to walk a table tree and count the columns
-func countColumns(node *blackfriday.Node) int {
-	var columns int
+// tableColumnAligns walks a Table node's header row, since tbl(1) requires
+// knowing the column count and alignment before outputting any row data.
+func tableColumnAligns(node *blackfriday.Node) []blackfriday.CellAlignFlags {
+	var aligns []blackfriday.CellAlignFlags
 
 	node.Walk(func(node *blackfriday.Node, entering bool) blackfriday.WalkStatus {
 		switch node.Type {
-		case blackfriday.TableRow:
+		case blackfriday.TableHead, blackfriday.TableRow:
+			// stop once the header row has closed; we don't need the body rows
 			if !entering {
 				return blackfriday.Terminate
 			}
 		case blackfriday.TableCell:
-			columns++
-		default:
-			return blackfriday.GoToNext
+			if entering {
+				aligns = append(aligns, node.Align)
+			}
 		}
-		return blackfriday.Terminate
+		return blackfriday.GoToNext
 	})
-	return columns
+	return aligns
+}
+
+// tableColumnSpec renders a tbl(1) format line for the given per-column
+// alignments. header bolds every column (via the "b" modifier) so that the
+// header row stands out without wrapping each cell in codespan tags.
+func (r *roffRenderer) tableColumnSpec(aligns []blackfriday.CellAlignFlags, header bool) string {
+	specs := make([]string, len(aligns))
+	for i, a := range aligns {
+		spec := columnAlignSpec(a)
+		if header {
+			spec += "b"
+		}
+		if i < len(r.tableWidths) && r.tableWidths[i] > 0 {
+			spec += fmt.Sprintf("w(%d)", r.tableWidths[i])
+		}
+		specs[i] = spec
+	}
+	return strings.Join(specs, " ")
+}
+
+func columnAlignSpec(a blackfriday.CellAlignFlags) string {
+	switch a {
+	case blackfriday.TableAlignmentRight:
+		return "r"
+	case blackfriday.TableAlignmentCenter:
+		return "c"
+	default:
+		return "l"
+	}
+}
+
+// tableCellNeedsWrap reports whether a table cell's contents must be wrapped
+// in tbl(1) T{/T} markers so that block content, formatting and line breaks
+// inside the cell are honored rather than flattened onto the table's line.
+func tableCellNeedsWrap(node *blackfriday.Node) bool {
+	if node.FirstChild == nil {
+		return false
+	}
+	if node.FirstChild.Next != nil {
+		return true
+	}
+	switch node.FirstChild.Type {
+	case blackfriday.Text, blackfriday.Code:
+		return false
+	default:
+		return true
+	}
 }
 
 func out(w io.Writer, output string) {
@@ -0,0 +1,36 @@
+package md2man
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestTableColumnAlignment(t *testing.T) {
+	input := []byte("| Left | Center | Right |\n|:---|:---:|---:|\n| a | b | c |\n")
+	out := string(Render(input))
+
+	if !strings.Contains(out, "lb cb rb\n") {
+		t.Fatalf("expected header format line with per-column alignment, got:\n%s", out)
+	}
+	if !strings.Contains(out, "l c r.\n") {
+		t.Fatalf("expected body format line with per-column alignment, got:\n%s", out)
+	}
+}
+
+func TestTableCellWrapping(t *testing.T) {
+	input := []byte("| Col1 | Col2 |\n|---|---|\n| plain | **bold** |\n")
+	out := string(Render(input))
+
+	if strings.Count(out, tableCellStart) != 1 {
+		t.Fatalf("expected exactly one cell (the formatted one) to be wrapped in T{/T}, got:\n%s", out)
+	}
+}
+
+func TestTableCellWithMixedInlineFormatting(t *testing.T) {
+	input := []byte("| Col1 |\n|---|\n| plain *and* emphasized |\n")
+	out := string(Render(input))
+
+	if !strings.Contains(out, tableCellStart) {
+		t.Fatalf("expected a cell mixing plain text and emphasis to be wrapped in T{/T}, got:\n%s", out)
+	}
+}
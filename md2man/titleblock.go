@@ -0,0 +1,127 @@
+package md2man
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+)
+
+// manTitleBlock holds the metadata needed to populate a man page's .TH
+// header line, parsed from an optional block at the start of the source
+// document.
+type manTitleBlock struct {
+	title   string
+	section string
+	date    string
+	source  string
+	manual  string
+}
+
+// thLine renders the .TH header line described by t.
+func (t *manTitleBlock) thLine() string {
+	return fmt.Sprintf("%s%s %s %s %s %s\n", titleHeader,
+		quoteTHField(strings.ToUpper(t.title)), quoteTHField(t.section),
+		quoteTHField(t.date), quoteTHField(t.source), quoteTHField(t.manual))
+}
+
+func quoteTHField(s string) string {
+	return `"` + strings.ReplaceAll(s, `"`, `\"`) + `"`
+}
+
+// parseTitleBlock looks for a leading pandoc-style title line
+// ("% NAME(SECTION) | DATE | SOURCE") or a YAML front-matter block
+// (delimited by "---" lines, with title/section/date/source/manual keys) at
+// the start of doc. When one is found it is stripped from the returned
+// document and recorded on r so RenderHeader can emit a full .TH line from
+// it; otherwise doc is returned unchanged and the .TH line is built from the
+// first level-1 heading, as before.
+func (r *roffRenderer) parseTitleBlock(doc []byte) []byte {
+	if rest, tb, ok := parseYAMLTitleBlock(doc); ok {
+		r.titleBlock = tb
+		return rest
+	}
+	if rest, tb, ok := parsePandocTitleLine(doc); ok {
+		r.titleBlock = tb
+		return rest
+	}
+	return doc
+}
+
+func firstLine(doc []byte) (line string, rest []byte, ok bool) {
+	if len(doc) == 0 {
+		return "", doc, false
+	}
+	if idx := bytes.IndexByte(doc, '\n'); idx >= 0 {
+		return string(doc[:idx]), doc[idx+1:], true
+	}
+	return string(doc), nil, true
+}
+
+func parsePandocTitleLine(doc []byte) ([]byte, *manTitleBlock, bool) {
+	line, rest, ok := firstLine(doc)
+	line = strings.TrimSpace(line)
+	if !ok || !strings.HasPrefix(line, "%") {
+		return doc, nil, false
+	}
+	line = strings.TrimSpace(strings.TrimPrefix(line, "%"))
+
+	open, shut := strings.IndexByte(line, '('), strings.IndexByte(line, ')')
+	if open < 0 || shut < open {
+		return doc, nil, false
+	}
+	tb := &manTitleBlock{
+		title:   strings.TrimSpace(line[:open]),
+		section: strings.TrimSpace(line[open+1 : shut]),
+	}
+
+	remainder := strings.TrimPrefix(strings.TrimSpace(line[shut+1:]), "|")
+	fields := strings.SplitN(remainder, "|", 2)
+	tb.date = strings.TrimSpace(fields[0])
+	if len(fields) > 1 {
+		tb.source = strings.TrimSpace(fields[1])
+	}
+	return rest, tb, true
+}
+
+func parseYAMLTitleBlock(doc []byte) ([]byte, *manTitleBlock, bool) {
+	trimmed := string(bytes.TrimLeft(doc, "\r\n"))
+	if !strings.HasPrefix(trimmed, "---") {
+		return doc, nil, false
+	}
+
+	lines := strings.Split(trimmed, "\n")
+	if strings.TrimSpace(lines[0]) != "---" {
+		return doc, nil, false
+	}
+
+	tb := &manTitleBlock{}
+	end := -1
+	for i := 1; i < len(lines); i++ {
+		line := strings.TrimSpace(lines[i])
+		if line == "---" {
+			end = i
+			break
+		}
+		fields := strings.SplitN(line, ":", 2)
+		if len(fields) != 2 {
+			continue
+		}
+		value := strings.TrimSpace(fields[1])
+		switch strings.ToLower(strings.TrimSpace(fields[0])) {
+		case "title":
+			tb.title = value
+		case "section":
+			tb.section = value
+		case "date":
+			tb.date = value
+		case "source":
+			tb.source = value
+		case "manual":
+			tb.manual = value
+		}
+	}
+	if end < 0 || tb.title == "" {
+		return doc, nil, false
+	}
+	return []byte(strings.Join(lines[end+1:], "\n")), tb, true
+}
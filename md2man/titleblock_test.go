@@ -0,0 +1,113 @@
+package md2man
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParsePandocTitleLineWithDateAndSource(t *testing.T) {
+	rest, tb, ok := parsePandocTitleLine([]byte("% mycmd(1) | 2021-01-02 | My Source\nbody\n"))
+	if !ok {
+		t.Fatal("expected the pandoc title line to be recognized")
+	}
+	if tb.title != "mycmd" || tb.section != "1" || tb.date != "2021-01-02" || tb.source != "My Source" {
+		t.Fatalf("unexpected fields: %+v", tb)
+	}
+	if string(rest) != "body\n" {
+		t.Fatalf("expected the title line to be stripped, got: %q", rest)
+	}
+}
+
+func TestParsePandocTitleLineWithoutDateAndSource(t *testing.T) {
+	rest, tb, ok := parsePandocTitleLine([]byte("% mycmd(1)\nbody\n"))
+	if !ok {
+		t.Fatal("expected the pandoc title line to be recognized")
+	}
+	if tb.title != "mycmd" || tb.section != "1" || tb.date != "" || tb.source != "" {
+		t.Fatalf("unexpected fields: %+v", tb)
+	}
+	if string(rest) != "body\n" {
+		t.Fatalf("expected the title line to be stripped, got: %q", rest)
+	}
+}
+
+func TestParsePandocTitleLineMalformed(t *testing.T) {
+	doc := []byte("% mycmd without parens\nbody\n")
+	rest, tb, ok := parsePandocTitleLine(doc)
+	if ok || tb != nil {
+		t.Fatalf("expected a malformed title line (missing section parens) to be rejected, got tb=%+v ok=%v", tb, ok)
+	}
+	if string(rest) != string(doc) {
+		t.Fatalf("expected doc to be returned unchanged, got: %q", rest)
+	}
+}
+
+func TestParseYAMLTitleBlockPartialFields(t *testing.T) {
+	rest, tb, ok := parseYAMLTitleBlock([]byte("---\ntitle: mycmd\nsection: 3\n---\nbody\n"))
+	if !ok {
+		t.Fatal("expected the YAML title block to be recognized")
+	}
+	if tb.title != "mycmd" || tb.section != "3" || tb.date != "" || tb.source != "" || tb.manual != "" {
+		t.Fatalf("unexpected fields: %+v", tb)
+	}
+	if string(rest) != "body\n" {
+		t.Fatalf("expected the YAML block to be stripped, got: %q", rest)
+	}
+}
+
+func TestParseYAMLTitleBlockMissingTitleFallsBack(t *testing.T) {
+	doc := []byte("---\nsection: 3\n---\nbody\n")
+	rest, tb, ok := parseYAMLTitleBlock(doc)
+	if ok || tb != nil {
+		t.Fatalf("expected a YAML block with no title: key to be rejected, got tb=%+v ok=%v", tb, ok)
+	}
+	if string(rest) != string(doc) {
+		t.Fatalf("expected doc to be returned unchanged so the H1 fallback can run, got: %q", rest)
+	}
+}
+
+func TestParseYAMLTitleBlockUnterminatedFallsBack(t *testing.T) {
+	doc := []byte("---\ntitle: mycmd\nbody with no closing delimiter\n")
+	rest, tb, ok := parseYAMLTitleBlock(doc)
+	if ok || tb != nil {
+		t.Fatalf("expected an unterminated YAML block to be rejected, got tb=%+v ok=%v", tb, ok)
+	}
+	if string(rest) != string(doc) {
+		t.Fatalf("expected doc to be returned unchanged, got: %q", rest)
+	}
+}
+
+func TestParseYAMLTitleBlockCRLF(t *testing.T) {
+	rest, tb, ok := parseYAMLTitleBlock([]byte("---\r\ntitle: mycmd\r\n---\r\nbody\r\n"))
+	if !ok {
+		t.Fatal("expected a CRLF YAML title block to be recognized")
+	}
+	if tb.title != "mycmd" {
+		t.Fatalf("unexpected title: %q", tb.title)
+	}
+	if string(rest) != "body\r\n" {
+		t.Fatalf("expected the YAML block to be stripped, got: %q", rest)
+	}
+}
+
+func TestRenderHeaderUsesTitleBlockAndSuppressesSecondTH(t *testing.T) {
+	out := string(Render([]byte("% mycmd(1) | 2021-01-02 | My Source\n\n# NAME\n\nmycmd - does a thing\n")))
+
+	if got := strings.Count(out, ".TH "); got != 1 {
+		t.Fatalf("expected exactly one .TH line, got %d in:\n%s", got, out)
+	}
+	if !strings.Contains(out, `.TH "MYCMD" "1" "2021-01-02" "My Source" ""`) {
+		t.Fatalf("expected a .TH line built from the title block, got:\n%s", out)
+	}
+	if !strings.Contains(out, topLevelHeader+"NAME") {
+		t.Fatalf("expected the first H1 to render as a regular section heading, got:\n%s", out)
+	}
+}
+
+func TestRenderHeaderFallsBackToH1WithoutTitleBlock(t *testing.T) {
+	out := string(Render([]byte("# mycmd(1)\n\nsome body text\n")))
+
+	if got := strings.Count(out, ".TH "); got != 1 {
+		t.Fatalf("expected exactly one .TH line from the H1 fallback, got %d in:\n%s", got, out)
+	}
+}